@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Amount is a JSON-string-encoded decimal amount. Every asset quantity or
+// price exchanged with the API uses this type instead of a raw string so
+// precision survives marshaling and callers don't hand-roll decimal parsing.
+type Amount struct {
+	decimal.Decimal
+}
+
+// NewAmount parses s into an Amount, returning an error if s is not a valid
+// decimal string.
+func NewAmount(s string) (Amount, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return Amount{}, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+	return Amount{d}, nil
+}
+
+// MustAmount is like NewAmount but panics on error; intended for literals.
+func MustAmount(s string) Amount {
+	a, err := NewAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// MarshalJSON encodes the amount as a JSON string to preserve precision.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.Decimal.String())
+}
+
+// UnmarshalJSON decodes a JSON string, or a bare JSON number for servers that
+// don't quote it, into an Amount.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		d, err := decimal.NewFromString(s)
+		if err != nil {
+			return fmt.Errorf("invalid amount %q: %w", s, err)
+		}
+		a.Decimal = d
+		return nil
+	}
+
+	var d decimal.Decimal
+	if err := json.Unmarshal(data, &d); err != nil {
+		return fmt.Errorf("invalid amount: %w", err)
+	}
+	a.Decimal = d
+	return nil
+}
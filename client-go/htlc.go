@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// HTLC order statuses for the atomic-swap settlement state machine.
+const (
+	HTLCStatusAwaiting         = "AWAITING_HTLC"
+	HTLCStatusLocked           = "LOCKED"
+	HTLCStatusPreimageRevealed = "PREIMAGE_REVEALED"
+	HTLCStatusSettled          = "SETTLED"
+	HTLCStatusRefunded         = "REFUNDED"
+)
+
+// SwapHTLCRequest represents a request to settle a swap via a hash-time-
+// locked contract instead of custodial transfer, so the destination leg can
+// be settled on-chain without trusting the counterparty.
+type SwapHTLCRequest struct {
+	From               string   `json:"from"`
+	To                 string   `json:"to"`
+	Amount             Amount   `json:"amount"`
+	Account            string   `json:"account"`
+	SlippageBps        int      `json:"slippage_bps"`
+	ClientOrderID      *string  `json:"clientOrderId,omitempty"`
+	Filter             []string `json:"filter,omitempty"`
+	PreimageHash       string   `json:"preimageHash"`
+	RefundPubKey       string   `json:"refundPubKey"`
+	Timeout            int64    `json:"timeout"` // unix timestamp after which Refund becomes valid
+	DestinationAddress string   `json:"destinationAddress"`
+}
+
+// SwapHTLCResponse represents the current state of an HTLC-settled swap.
+// FundingScript or Invoice is populated depending on whether the
+// counterparty leg is on-chain or off-chain.
+type SwapHTLCResponse struct {
+	OrderID       string `json:"orderId"`
+	Status        string `json:"status"`
+	FundingScript string `json:"fundingScript,omitempty"`
+	Invoice       string `json:"invoice,omitempty"`
+	UpdatedAt     int64  `json:"updatedAt"`
+}
+
+// SwapHTLC initiates an HTLC-settled swap. The returned response carries
+// either a funding-tx script or an invoice to pay to lock the source leg;
+// the order then transitions AWAITING_HTLC -> LOCKED -> PREIMAGE_REVEALED ->
+// SETTLED/REFUNDED, observable via GetOrderStatus or RevealPreimage/Refund.
+func (c *BrokerClient) SwapHTLC(ctx context.Context, req *SwapHTLCRequest, idempotencyKey string) (*SwapHTLCResponse, error) {
+	rounded, err := c.validateAmount(swapSymbol(req.From, req.To), req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	req.Amount = rounded
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	responseBody, err := c.makeRequestWithIdempotencyKey(ctx, "POST", "/api/v1/swap/htlc", body, idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiErr := checkAPIError(responseBody); apiErr != nil {
+		return nil, apiErr
+	}
+
+	var response SwapHTLCResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// RevealPreimage submits the preimage for an HTLC order once the
+// counterparty's leg has been observed LOCKED, moving the order to
+// PREIMAGE_REVEALED and triggering settlement.
+func (c *BrokerClient) RevealPreimage(ctx context.Context, orderID, preimage string) (*SwapHTLCResponse, error) {
+	body, err := json.Marshal(map[string]string{"preimage": preimage})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	path := fmt.Sprintf("/api/v1/swap/htlc/%s/reveal", orderID)
+	responseBody, err := c.makeRequest(ctx, "POST", path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiErr := checkAPIError(responseBody); apiErr != nil {
+		return nil, apiErr
+	}
+
+	var response SwapHTLCResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// Refund claims back the locked funds of an HTLC order once its timeout has
+// elapsed without the preimage being revealed.
+func (c *BrokerClient) Refund(ctx context.Context, orderID string) (*SwapHTLCResponse, error) {
+	path := fmt.Sprintf("/api/v1/swap/htlc/%s/refund", orderID)
+	responseBody, err := c.makeRequest(ctx, "POST", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiErr := checkAPIError(responseBody); apiErr != nil {
+		return nil, apiErr
+	}
+
+	var response SwapHTLCResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
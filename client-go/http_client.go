@@ -1,19 +1,70 @@
-package client_go
+package main
 
 import (
 	"bytes"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 )
 
-// DefaultHTTPClient реализует интерфейс HTTPClient
+// HTTPClient is the transport BrokerClient issues every request through. It
+// takes a pre-built body and header set and returns the raw response body,
+// so request signing and idempotency keys live in BrokerClient rather than
+// here; retries live either in BrokerClient (its RetryPolicy) or in a
+// RetryingHTTPClient wrapper. Request bodies are passed as []byte (never
+// a stream), so a RetryingHTTPClient can resend the same bytes on retry
+// without needing to rewind anything. Implementations should report a
+// non-2xx response as an *HTTPStatusError so retry policies can read the
+// status code and Retry-After value.
+type HTTPClient interface {
+	Do(method, url string, headers map[string]string, body []byte) ([]byte, error)
+}
+
+// HTTPStatusError is returned by HTTPClient.Do when the server responds with
+// a non-2xx status. It carries the status code and any Retry-After value so
+// retry policies can react to them without re-parsing the error string.
+type HTTPStatusError struct {
+	StatusCode int
+	Body       []byte
+	// RetryAfter is the server's requested delay before retrying, parsed
+	// from the Retry-After header (delta-seconds or HTTP-date). Zero if the
+	// header was absent or unparseable.
+	RetryAfter time.Duration
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("HTTP error %d: %s", e.StatusCode, string(e.Body))
+}
+
+// parseRetryAfter parses a Retry-After header value, which per RFC 7231 is
+// either an integer number of seconds or an HTTP-date. It returns zero if
+// value is empty or matches neither form.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// DefaultHTTPClient implements HTTPClient on top of net/http.
 type DefaultHTTPClient struct {
 	client *http.Client
 }
 
-// NewDefaultHTTPClient создает новый HTTP клиент
+// NewDefaultHTTPClient creates a new HTTP client with a 30s timeout.
 func NewDefaultHTTPClient() *DefaultHTTPClient {
 	return &DefaultHTTPClient{
 		client: &http.Client{
@@ -22,7 +73,7 @@ func NewDefaultHTTPClient() *DefaultHTTPClient {
 	}
 }
 
-// Do выполняет HTTP запрос
+// Do performs an HTTP request.
 func (c *DefaultHTTPClient) Do(method, url string, headers map[string]string, body []byte) ([]byte, error) {
 	var reqBody io.Reader
 	if body != nil {
@@ -34,7 +85,6 @@ func (c *DefaultHTTPClient) Do(method, url string, headers map[string]string, bo
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Добавляем заголовки
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
@@ -50,9 +100,12 @@ func (c *DefaultHTTPClient) Do(method, url string, headers map[string]string, bo
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	// Проверяем статус код
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return responseBody, fmt.Errorf("HTTP error %d: %s", resp.StatusCode, string(responseBody))
+		return responseBody, &HTTPStatusError{
+			StatusCode: resp.StatusCode,
+			Body:       responseBody,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
 
 	return responseBody, nil
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// SymbolInfo describes the tradeable constraints the server enforces for a
+// symbol: minimum order size and the amount tick size. Swap/estimate/route
+// requests only ever carry an amount, never a price, so there is no
+// PriceTickSize here to validate client-side; price discovery and
+// enforcement stay server-side.
+type SymbolInfo struct {
+	Symbol         string `json:"symbol"`
+	AmountTickSize Amount `json:"amountTickSize"`
+	MinAmount      Amount `json:"minAmount"`
+}
+
+// InstrumentsResponse is the payload returned by GetInstruments.
+type InstrumentsResponse struct {
+	Instruments []SymbolInfo `json:"instruments"`
+}
+
+// ErrAmountBelowMin is returned when a requested amount is smaller than the
+// symbol's configured minimum.
+type ErrAmountBelowMin struct {
+	Symbol string
+	Amount Amount
+	Min    Amount
+}
+
+func (e *ErrAmountBelowMin) Error() string {
+	return fmt.Sprintf("amount %s for %s is below minimum %s", e.Amount.String(), e.Symbol, e.Min.String())
+}
+
+// instrumentRegistry caches SymbolInfo fetched via GetInstruments so swap and
+// estimate requests can be rounded and validated client-side before signing,
+// instead of only discovering a violation after a round-trip API rejection.
+type instrumentRegistry struct {
+	mu      sync.RWMutex
+	symbols map[string]SymbolInfo
+}
+
+func newInstrumentRegistry() *instrumentRegistry {
+	return &instrumentRegistry{symbols: make(map[string]SymbolInfo)}
+}
+
+func (r *instrumentRegistry) set(infos []SymbolInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, info := range infos {
+		r.symbols[info.Symbol] = info
+	}
+}
+
+func (r *instrumentRegistry) get(symbol string) (SymbolInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	info, ok := r.symbols[symbol]
+	return info, ok
+}
+
+// GetInstruments fetches tick-size metadata for every tradeable symbol and
+// caches it for subsequent EstimateSwap/Swap validation.
+func (c *BrokerClient) GetInstruments(ctx context.Context) (*InstrumentsResponse, error) {
+	responseBody, err := c.makeRequest(ctx, "GET", "/api/v1/instruments", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiErr := checkAPIError(responseBody); apiErr != nil {
+		return nil, apiErr
+	}
+
+	var response InstrumentsResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	c.instruments.set(response.Instruments)
+	return &response, nil
+}
+
+// validateAmount rounds amount down to the cached SymbolInfo's
+// AmountTickSize for symbol, if one has been loaded via GetInstruments, and
+// checks the rounded amount against MinAmount. Symbols that haven't been
+// fetched yet are returned unrounded and unvalidated; the server remains the
+// source of truth. Callers must submit the returned Amount, not the original.
+func (c *BrokerClient) validateAmount(symbol string, amount Amount) (Amount, error) {
+	info, ok := c.instruments.get(symbol)
+	if !ok {
+		return amount, nil
+	}
+
+	rounded := Amount{Decimal: roundDownToTick(amount.Decimal, info.AmountTickSize.Decimal)}
+
+	if rounded.LessThan(info.MinAmount.Decimal) {
+		return amount, &ErrAmountBelowMin{Symbol: symbol, Amount: rounded, Min: info.MinAmount}
+	}
+
+	return rounded, nil
+}
+
+// roundDownToTick floors value to the nearest multiple of step at or below
+// it, the same direction exchanges round order sizes in so a rounded amount
+// never exceeds what the caller asked to spend.
+func roundDownToTick(value, step decimal.Decimal) decimal.Decimal {
+	if step.IsZero() {
+		return value
+	}
+	return value.Div(step).Floor().Mul(step)
+}
+
+// swapSymbol builds the registry lookup key for a from/to asset pair.
+func swapSymbol(from, to string) string {
+	return from + "-" + to
+}
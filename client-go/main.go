@@ -2,9 +2,7 @@ package main
 
 import (
 	"context"
-	"crypto/hmac"
 	"crypto/sha256"
-	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -17,32 +15,63 @@ import (
 
 // BrokerClient represents the client for TheOne Trading API
 type BrokerClient struct {
-	apiKey    string
-	secretKey string
-	baseURL   string
-	client    HTTPClient
+	apiKey      string
+	secretKey   string
+	baseURL     string
+	client      HTTPClient
+	signer      Signer
+	rateLimiter RateLimiter
+	retry       *RetryPolicy
+	logger      Logger
+	instruments *instrumentRegistry
 }
 
-// HTTPClient interface for HTTP client
-type HTTPClient interface {
-	Do(method, url string, headers map[string]string, body []byte) ([]byte, error)
+// NewBrokerClient creates a new client instance with default options: no
+// rate limiting, no retries, and a no-op logger.
+func NewBrokerClient(apiKey, secretKey, baseURL string, httpClient HTTPClient) *BrokerClient {
+	return NewBrokerClientWithOptions(apiKey, secretKey, baseURL, httpClient, nil)
 }
 
-// NewBrokerClient creates a new client instance
-func NewBrokerClient(apiKey, secretKey, baseURL string, httpClient HTTPClient) *BrokerClient {
+// NewBrokerClientWithOptions creates a new client instance with an optional
+// rate limiter, retry policy, logger and HTTP middleware chain. Pass nil to
+// use the defaults from NewBrokerClient.
+func NewBrokerClientWithOptions(apiKey, secretKey, baseURL string, httpClient HTTPClient, opts *ClientOptions) *BrokerClient {
+	if opts == nil {
+		opts = &ClientOptions{}
+	}
+
+	for _, mw := range opts.Middlewares {
+		httpClient = mw(httpClient)
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = noopLogger{}
+	}
+
+	signer := opts.Signer
+	if signer == nil {
+		signer = NewHMACSigner(apiKey, secretKey)
+	}
+
 	return &BrokerClient{
-		apiKey:    apiKey,
-		secretKey: secretKey,
-		baseURL:   baseURL,
-		client:    httpClient,
+		apiKey:      apiKey,
+		secretKey:   secretKey,
+		baseURL:     baseURL,
+		client:      httpClient,
+		signer:      signer,
+		rateLimiter: opts.RateLimiter,
+		retry:       opts.Retry,
+		logger:      logger,
+		instruments: newInstrumentRegistry(),
 	}
 }
 
 // Balance represents asset balance
 type Balance struct {
 	Asset  string `json:"asset"`
-	Total  string `json:"total"`
-	Locked string `json:"locked"`
+	Total  Amount `json:"total"`
+	Locked Amount `json:"locked"`
 }
 
 // BalanceResponse represents response for balances request
@@ -54,7 +83,7 @@ type BalanceResponse struct {
 type EstimateRequestHTTP struct {
 	From    string   `json:"from"`
 	To      string   `json:"to"`
-	Amount  string   `json:"amount"`
+	Amount  Amount   `json:"amount"`
 	Network string   `json:"network"`
 	Account *string  `json:"account,omitempty"`
 	Filter  []string `json:"filter,omitempty"` // Liquidity sources filter (binance, bybit, gate)
@@ -66,15 +95,15 @@ type RouteStep struct {
 	Pool      string `json:"pool"`
 	FromAsset string `json:"from_asset"`
 	ToAsset   string `json:"to_asset"`
-	AmountIn  string `json:"amount_in"`
-	AmountOut string `json:"amount_out"`
+	AmountIn  Amount `json:"amount_in"`
+	AmountOut Amount `json:"amount_out"`
 }
 
 // EstimateResponse represents response for estimation request
 type EstimateResponse struct {
 	Route       []RouteStep `json:"route"`
-	Price       string      `json:"price"`
-	ExpectedOut string      `json:"expectedOut"`
+	Price       Amount      `json:"price"`
+	ExpectedOut Amount      `json:"expectedOut"`
 	ExpiresAt   int64       `json:"expiresAt"`
 }
 
@@ -82,7 +111,7 @@ type EstimateResponse struct {
 type SwapRequestHTTP struct {
 	From          string   `json:"from"`
 	To            string   `json:"to"`
-	Amount        string   `json:"amount"`
+	Amount        Amount   `json:"amount"`
 	Account       string   `json:"account"`
 	SlippageBps   int      `json:"slippage_bps"`
 	ClientOrderID *string  `json:"clientOrderId,omitempty"`
@@ -117,24 +146,20 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("API Error [%s]: %s (RequestID: %s)", e.Code, e.Message, e.RequestID)
 }
 
-// generateSignature creates HMAC-SHA256 signature
-func (c *BrokerClient) generateSignature(method, pathWithQuery string, timestamp int64, nonce, bodySHA256 string) string {
-	canonicalString := fmt.Sprintf("%s\n%s\n%d\n%s\n%s",
-		strings.ToUpper(method),
-		pathWithQuery,
-		timestamp,
-		nonce,
-		bodySHA256,
-	)
-
-	hash := sha256.Sum256([]byte(c.secretKey))
-	secretKeyBase64 := base64.URLEncoding.EncodeToString(hash[:])
-	hmacKey := []byte(secretKeyBase64)
-
-	mac := hmac.New(sha256.New, hmacKey)
-	mac.Write([]byte(canonicalString))
-	signature := hex.EncodeToString(mac.Sum(nil))
-	return signature
+// generateSignature signs the canonical request string with the client's
+// configured Signer (HMAC-SHA256 by default) and returns the signature
+// header value plus the key ID to send as X-API-KEY.
+func (c *BrokerClient) generateSignature(method, pathWithQuery string, timestamp int64, nonce, bodySHA256 string) (string, string, error) {
+	canonicalString := BuildCanonicalString(strings.ToUpper(method), pathWithQuery, timestamp, nonce, bodySHA256)
+
+	signature, keyID, err := c.signer.Sign(canonicalString)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	c.logger.Debug("signed %s %s: canonical=%q signature=%s", strings.ToUpper(method), pathWithQuery, canonicalString, signature)
+
+	return signature, keyID, nil
 }
 
 // hashBody creates SHA256 hash of request body
@@ -150,27 +175,90 @@ func generateNonce() string {
 
 // makeRequest makes authenticated request to API
 func (c *BrokerClient) makeRequest(ctx context.Context, method, path string, body []byte) ([]byte, error) {
-	timestamp := time.Now().UnixMilli() // Using UnixMilli() as in the test
-	nonce := generateNonce()
-	bodySHA256 := hashBody(body)
-
-	signature := c.generateSignature(method, path, timestamp, nonce, bodySHA256)
-
-	headers := map[string]string{
-		"Content-Type":    "application/json",
-		"X-API-KEY":       c.apiKey,
-		"X-API-TIMESTAMP": strconv.FormatInt(timestamp, 10),
-		"X-API-NONCE":     nonce,
-		"X-API-SIGN":      signature,
+	return c.makeRequestWithIdempotencyKey(ctx, method, path, body, "")
+}
+
+// makeRequestWithIdempotencyKey is like makeRequest but also attaches an
+// Idempotency-Key header when idempotencyKey is non-empty, which makes the
+// request eligible for retries even for non-idempotent HTTP methods.
+func (c *BrokerClient) makeRequestWithIdempotencyKey(ctx context.Context, method, path string, body []byte, idempotencyKey string) ([]byte, error) {
+	retryable := isIdempotentMethod(method) || idempotencyKey != ""
+
+	var lastErr error
+	attempts := 1
+	if c.retry != nil && retryable {
+		attempts = c.retry.MaxRetries + 1
 	}
 
-	url := c.baseURL + path
-	responseBody, err := c.client.Do(method, url, headers, body)
-	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			delay := c.retry.backoff(attempt - 1)
+			if ra, ok := retryAfterDelay(lastErr); ok {
+				delay = ra
+			}
+			c.logger.Warn("retrying %s %s (attempt %d/%d) after %s: %v", method, path, attempt+1, attempts, delay, lastErr)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		if c.rateLimiter != nil {
+			if err := c.rateLimiter.Wait(ctx, path); err != nil {
+				return nil, fmt.Errorf("rate limiter: %w", err)
+			}
+		}
+
+		timestamp := time.Now().UnixMilli() // Using UnixMilli() as in the test
+		nonce := generateNonce()
+		bodySHA256 := hashBody(body)
+
+		signature, keyID, err := c.generateSignature(method, path, timestamp, nonce, bodySHA256)
+		if err != nil {
+			return nil, err
+		}
+
+		headers := map[string]string{
+			"Content-Type":    "application/json",
+			"X-API-KEY":       keyID,
+			"X-API-TIMESTAMP": strconv.FormatInt(timestamp, 10),
+			"X-API-NONCE":     nonce,
+			"X-API-SIGN":      signature,
+			"X-API-SIGN-ALG":  c.signer.Algorithm(),
+		}
+		if idempotencyKey != "" {
+			headers["Idempotency-Key"] = idempotencyKey
+		}
+
+		url := c.baseURL + path
+		responseBody, err := c.client.Do(method, url, headers, body)
+		if err == nil {
+			return responseBody, nil
+		}
+
+		lastErr = fmt.Errorf("HTTP request failed: %w", err)
+		shouldRetry := isRetryableErr
+		if c.retry != nil && c.retry.ShouldRetry != nil {
+			shouldRetry = c.retry.ShouldRetry
+		}
+		if !shouldRetry(err) {
+			return nil, lastErr
+		}
 	}
 
-	return responseBody, nil
+	return nil, lastErr
+}
+
+// isIdempotentMethod reports whether method is safe to retry without an
+// explicit Idempotency-Key.
+func isIdempotentMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "PUT", "DELETE":
+		return true
+	default:
+		return false
+	}
 }
 
 // checkAPIError checks if response is an API error
@@ -203,6 +291,12 @@ func (c *BrokerClient) GetBalances(ctx context.Context) (*BalanceResponse, error
 
 // EstimateSwap gets swap estimation
 func (c *BrokerClient) EstimateSwap(ctx context.Context, req *EstimateRequestHTTP) (*EstimateResponse, error) {
+	rounded, err := c.validateAmount(swapSymbol(req.From, req.To), req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	req.Amount = rounded
+
 	body, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -227,30 +321,20 @@ func (c *BrokerClient) EstimateSwap(ctx context.Context, req *EstimateRequestHTT
 
 // Swap executes swap
 func (c *BrokerClient) Swap(ctx context.Context, req *SwapRequestHTTP, idempotencyKey string) (*SwapResponse, error) {
-	body, err := json.Marshal(req)
+	rounded, err := c.validateAmount(swapSymbol(req.From, req.To), req.Amount)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, err
 	}
+	req.Amount = rounded
 
-	timestamp := time.Now().UnixMilli() // Using UnixMilli() as in the test
-	nonce := generateNonce()
-	bodySHA256 := hashBody(body)
-
-	signature := c.generateSignature("POST", "/api/v1/swap", timestamp, nonce, bodySHA256)
-
-	headers := map[string]string{
-		"Content-Type":    "application/json",
-		"X-API-KEY":       c.apiKey,
-		"X-API-TIMESTAMP": strconv.FormatInt(timestamp, 10),
-		"X-API-NONCE":     nonce,
-		"X-API-SIGN":      signature,
-		"Idempotency-Key": idempotencyKey,
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	url := c.baseURL + "/api/v1/swap"
-	responseBody, err := c.client.Do("POST", url, headers, body)
+	responseBody, err := c.makeRequestWithIdempotencyKey(ctx, "POST", "/api/v1/swap", body, idempotencyKey)
 	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+		return nil, err
 	}
 
 	if apiErr := checkAPIError(responseBody); apiErr != nil {
@@ -318,7 +402,7 @@ func main() {
 	estimateReq := &EstimateRequestHTTP{
 		From:   "TRX",
 		To:     "USDT",
-		Amount: "10",
+		Amount: MustAmount("10"),
 		Filter: []string{"binance", "gate"}, // Use specific liquidity sources
 	}
 
@@ -347,21 +431,22 @@ func main() {
 		} else {
 			fmt.Printf("Swap created: %+v\n", swapResponse)
 
-			// Example 4: Checking order status
-			fmt.Println("\n=== Checking order status ===")
-			for i := 0; i < 10; i++ {
-				orderStatus, err := client.GetOrderStatus(ctx, swapResponse.OrderID, nil)
-				if err != nil {
-					log.Printf("Error getting order status: %v\n", err)
-				} else {
-					if orderStatus.Status == "PENDING" {
-						fmt.Printf("Order %s is pending\n", orderStatus.OrderID)
-						<-time.After(5 * time.Second)
-						continue
+			// Example 4: Streaming order status instead of polling
+			fmt.Println("\n=== Streaming order updates ===")
+			streamCtx, cancelStream := context.WithTimeout(ctx, 60*time.Second)
+			defer cancelStream()
+
+			events, err := client.Stream(streamCtx)
+			if err != nil {
+				log.Printf("Error opening stream: %v\n", err)
+			} else {
+				for event := range events {
+					if event.Type == StreamEventOrder && event.Order.OrderID == swapResponse.OrderID {
+						fmt.Printf("Order status: %+v\n", event.Order)
+						if event.Order.Status == "FILLED" || event.Order.Status == "FAILED" {
+							cancelStream()
+						}
 					}
-
-					fmt.Printf("Order status: %+v\n", orderStatus)
-					break
 				}
 			}
 		}
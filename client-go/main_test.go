@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeHTTPClient replays a fixed sequence of responses, one per call,
+// standing in for a real server's retryable-then-succeeds behavior.
+type fakeHTTPClient struct {
+	responses []fakeResponse
+	calls     int
+}
+
+type fakeResponse struct {
+	body []byte
+	err  error
+}
+
+func (f *fakeHTTPClient) Do(method, url string, headers map[string]string, body []byte) ([]byte, error) {
+	resp := f.responses[f.calls]
+	f.calls++
+	return resp.body, resp.err
+}
+
+// fakeRateLimiter counts how many times Wait is invoked instead of actually
+// throttling, so tests can assert it's consulted on every retry attempt.
+type fakeRateLimiter struct {
+	waits int
+}
+
+func (f *fakeRateLimiter) Wait(ctx context.Context, endpoint string) error {
+	f.waits++
+	return nil
+}
+
+func TestMakeRequestRateLimiterCalledPerAttempt(t *testing.T) {
+	retryableErr := &HTTPStatusError{StatusCode: 503}
+	httpClient := &fakeHTTPClient{responses: []fakeResponse{
+		{err: retryableErr},
+		{err: retryableErr},
+		{body: []byte(`{}`)},
+	}}
+	limiter := &fakeRateLimiter{}
+	c := NewBrokerClientWithOptions("key", "secret", "https://example.test", httpClient, &ClientOptions{
+		RateLimiter: limiter,
+		Retry:       &RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond},
+	})
+
+	if _, err := c.makeRequest(context.Background(), "GET", "/api/v1/balances", nil); err != nil {
+		t.Fatalf("makeRequest failed: %v", err)
+	}
+
+	if httpClient.calls != 3 {
+		t.Fatalf("HTTPClient.Do called %d times, want 3", httpClient.calls)
+	}
+	if limiter.waits != 3 {
+		t.Errorf("rate limiter Wait called %d times, want 3 (once per attempt, not once total)", limiter.waits)
+	}
+}
+
+func TestMakeRequestHonorsRetryAfter(t *testing.T) {
+	httpClient := &fakeHTTPClient{responses: []fakeResponse{
+		{err: &HTTPStatusError{StatusCode: 429, RetryAfter: 5 * time.Millisecond}},
+		{body: []byte(`{}`)},
+	}}
+	c := NewBrokerClientWithOptions("key", "secret", "https://example.test", httpClient, &ClientOptions{
+		Retry: &RetryPolicy{MaxRetries: 1, BaseDelay: time.Second, MaxDelay: 2 * time.Second},
+	})
+
+	start := time.Now()
+	if _, err := c.makeRequest(context.Background(), "GET", "/api/v1/balances", nil); err != nil {
+		t.Fatalf("makeRequest failed: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("retry waited %s, want the 5ms Retry-After to win over the ~1s computed backoff", elapsed)
+	}
+}
+
+func TestMakeRequestStopsOnNonRetryableError(t *testing.T) {
+	httpClient := &fakeHTTPClient{responses: []fakeResponse{
+		{err: &HTTPStatusError{StatusCode: 400}},
+	}}
+	c := NewBrokerClientWithOptions("key", "secret", "https://example.test", httpClient, &ClientOptions{
+		Retry: &RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond},
+	})
+
+	if _, err := c.makeRequest(context.Background(), "GET", "/api/v1/balances", nil); err == nil {
+		t.Fatal("expected error for a non-retryable 400 response")
+	}
+
+	if httpClient.calls != 1 {
+		t.Errorf("HTTPClient.Do called %d times, want 1 (no retry on a non-retryable error)", httpClient.calls)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Logger is the structured logging hook used throughout BrokerClient. The
+// noisy fmt.Printf calls that used to live in generateSignature are now only
+// emitted through Logger.Debug, so callers can silence them or route them to
+// their own logging stack.
+type Logger interface {
+	Debug(format string, args ...interface{})
+	Info(format string, args ...interface{})
+	Warn(format string, args ...interface{})
+	Error(format string, args ...interface{})
+}
+
+// noopLogger is the default Logger: it discards everything.
+type noopLogger struct{}
+
+func (noopLogger) Debug(format string, args ...interface{}) {}
+func (noopLogger) Info(format string, args ...interface{})  {}
+func (noopLogger) Warn(format string, args ...interface{})  {}
+func (noopLogger) Error(format string, args ...interface{}) {}
+
+// RateLimiter throttles outgoing requests, e.g. a token-bucket with per-
+// endpoint weights similar to an exchange's weighted quota system. Wait
+// should block (respecting ctx) until the request identified by endpoint is
+// allowed to proceed.
+type RateLimiter interface {
+	Wait(ctx context.Context, endpoint string) error
+}
+
+// RetryPolicy controls jittered exponential backoff for failed requests.
+// Retries only apply to idempotent methods (GET/HEAD/PUT/DELETE) or requests
+// carrying an Idempotency-Key. A 429/503 response's Retry-After value, if
+// present, overrides the computed backoff delay for that attempt.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	// ShouldRetry decides whether a failed attempt should be retried. If
+	// nil, callers fall back to their own default (BrokerClient uses
+	// isRetryableErr directly; RetryingHTTPClient uses defaultShouldRetry,
+	// which additionally treats transient transport errors as retryable).
+	// Setting ShouldRetry overrides that default for both consumers, so a
+	// single RetryPolicy behaves consistently regardless of which one is
+	// in use.
+	ShouldRetry func(err error) bool
+}
+
+// DefaultRetryPolicy returns a conservative retry policy: 3 retries (4
+// attempts in all), starting at 200ms and capping at 5s.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  200 * time.Millisecond,
+		MaxDelay:   5 * time.Second,
+	}
+}
+
+// backoff returns the jittered delay before retry attempt (0-indexed).
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay * time.Duration(1<<uint(attempt))
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay/2 + jitter
+}
+
+// HTTPClientMiddleware wraps an HTTPClient to add cross-cutting behavior
+// such as tracing or metrics around every Do call.
+type HTTPClientMiddleware func(HTTPClient) HTTPClient
+
+// ClientOptions configures the optional, injectable pieces of BrokerClient:
+// rate limiting, retry policy, structured logging and HTTP middleware.
+type ClientOptions struct {
+	Signer      Signer
+	RateLimiter RateLimiter
+	Retry       *RetryPolicy
+	Logger      Logger
+	Middlewares []HTTPClientMiddleware
+}
+
+var httpStatusPattern = regexp.MustCompile(`HTTP error (\d+):`)
+
+// isRetryableErr reports whether err looks like a 429 or 5xx HTTP response.
+// It prefers the typed *HTTPStatusError returned by DefaultHTTPClient, but
+// falls back to pattern-matching the error text so a hand-rolled HTTPClient
+// that just formats "HTTP error <code>: ..." is still retried correctly.
+func isRetryableErr(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		code := statusErr.StatusCode
+		return code == 429 || (code >= 500 && code < 600)
+	}
+
+	m := httpStatusPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return false
+	}
+	code, convErr := strconv.Atoi(m[1])
+	if convErr != nil {
+		return false
+	}
+	return code == 429 || (code >= 500 && code < 600)
+}
+
+// retryAfterDelay extracts the server-requested retry delay from err, if
+// any. Callers that get one should wait that long instead of their own
+// computed backoff, per RFC 7231's Retry-After semantics.
+func retryAfterDelay(err error) (time.Duration, bool) {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+		return statusErr.RetryAfter, true
+	}
+	return 0, false
+}
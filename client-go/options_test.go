@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIsRetryableErr(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"typed 429", &HTTPStatusError{StatusCode: 429}, true},
+		{"typed 503", &HTTPStatusError{StatusCode: 503}, true},
+		{"typed 400", &HTTPStatusError{StatusCode: 400}, false},
+		{"string-formatted 500", errors.New("HTTP error 500: internal error"), true},
+		{"string-formatted 404", errors.New("HTTP error 404: not found"), false},
+		{"unrelated error", errors.New("request failed: dial tcp: timeout"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableErr(tt.err); got != tt.want {
+				t.Errorf("isRetryableErr(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfterDelay(t *testing.T) {
+	if d, ok := retryAfterDelay(nil); ok || d != 0 {
+		t.Errorf("retryAfterDelay(nil) = (%v, %v), want (0, false)", d, ok)
+	}
+
+	if d, ok := retryAfterDelay(&HTTPStatusError{StatusCode: 429}); ok || d != 0 {
+		t.Errorf("retryAfterDelay with no Retry-After = (%v, %v), want (0, false)", d, ok)
+	}
+
+	statusErr := &HTTPStatusError{StatusCode: 429, RetryAfter: 2 * time.Second}
+	d, ok := retryAfterDelay(statusErr)
+	if !ok || d != 2*time.Second {
+		t.Errorf("retryAfterDelay(%v) = (%v, %v), want (2s, true)", statusErr, d, ok)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		d := p.backoff(attempt)
+		if d <= 0 {
+			t.Errorf("backoff(%d) = %s, want > 0", attempt, d)
+		}
+		if d > p.MaxDelay {
+			t.Errorf("backoff(%d) = %s, want <= MaxDelay (%s)", attempt, d, p.MaxDelay)
+		}
+	}
+}
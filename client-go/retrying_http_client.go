@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// RetryingHTTPClient wraps an HTTPClient with the same jittered exponential
+// backoff BrokerClient uses internally, so any HTTPClient (a test double, a
+// logging/metrics/tracing decorator, a hand-rolled transport) gains retry
+// behavior without reimplementing it. Request bodies are already []byte, so
+// every retry resends the exact same bytes as the first attempt.
+type RetryingHTTPClient struct {
+	next   HTTPClient
+	policy *RetryPolicy
+}
+
+// NewRetryingHTTPClient wraps next with retry/backoff governed by policy. A
+// nil policy uses DefaultRetryPolicy.
+func NewRetryingHTTPClient(next HTTPClient, policy *RetryPolicy) *RetryingHTTPClient {
+	if policy == nil {
+		policy = DefaultRetryPolicy()
+	}
+	return &RetryingHTTPClient{next: next, policy: policy}
+}
+
+// Do performs the request, retrying on a 5xx/429 response or a transient
+// network error up to policy.MaxRetries times. Only idempotent methods and
+// requests carrying an Idempotency-Key header are retried; any other request
+// is attempted once.
+func (c *RetryingHTTPClient) Do(method, url string, headers map[string]string, body []byte) ([]byte, error) {
+	shouldRetry := c.policy.ShouldRetry
+	if shouldRetry == nil {
+		shouldRetry = defaultShouldRetry
+	}
+
+	var lastErr error
+	attempts := 1
+	if isIdempotentMethod(method) || headers["Idempotency-Key"] != "" {
+		attempts = c.policy.MaxRetries + 1
+	}
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			delay := c.policy.backoff(attempt - 1)
+			if ra, ok := retryAfterDelay(lastErr); ok {
+				delay = ra
+			}
+			time.Sleep(delay)
+		}
+
+		responseBody, err := c.next.Do(method, url, headers, body)
+		if err == nil {
+			return responseBody, nil
+		}
+
+		lastErr = err
+		if !shouldRetry(err) {
+			return responseBody, err
+		}
+	}
+
+	return nil, lastErr
+}
+
+// defaultShouldRetry retries HTTP 429/5xx responses (via isRetryableErr) and
+// transient transport failures, which DefaultHTTPClient reports as "request
+// failed: ..." rather than an HTTP status.
+func defaultShouldRetry(err error) bool {
+	if err == nil {
+		return false
+	}
+	if isRetryableErr(err) {
+		return true
+	}
+	return strings.HasPrefix(err.Error(), "request failed:")
+}
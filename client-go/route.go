@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// PlanRouteRequest requests multiple candidate routes for a swap, ranked by
+// expected output, instead of the single route EstimateSwap returns.
+type PlanRouteRequest struct {
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	Amount    Amount   `json:"amount"`
+	Network   string   `json:"network"`
+	Account   *string  `json:"account,omitempty"`
+	Filter    []string `json:"filter,omitempty"` // Liquidity sources filter (binance, bybit, gate)
+	MaxRoutes int      `json:"maxRoutes,omitempty"`
+}
+
+// CandidateRoute is one ranked route returned by PlanRoute.
+type CandidateRoute struct {
+	Route       []RouteStep `json:"route"`
+	Venues      []string    `json:"venues"`
+	Price       Amount      `json:"price"`
+	ExpectedOut Amount      `json:"expectedOut"`
+	ExpiresAt   int64       `json:"expiresAt"`
+}
+
+// PlanRouteResponse carries candidate routes ordered best-first by
+// ExpectedOut.
+type PlanRouteResponse struct {
+	Candidates []CandidateRoute `json:"candidates"`
+}
+
+// PlanRoute returns multiple candidate routes for a swap, ranked by expected
+// output, so callers can pick a route (or feed legs into SwapSplit) instead
+// of accepting whatever single route EstimateSwap finds.
+func (c *BrokerClient) PlanRoute(ctx context.Context, req *PlanRouteRequest) (*PlanRouteResponse, error) {
+	rounded, err := c.validateAmount(swapSymbol(req.From, req.To), req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	req.Amount = rounded
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	responseBody, err := c.makeRequest(ctx, "POST", "/api/v1/route/plan", body)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiErr := checkAPIError(responseBody); apiErr != nil {
+		return nil, apiErr
+	}
+
+	var response PlanRouteResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// SwapSplitLeg is one parallel leg of a split swap, executed against a
+// single liquidity source with its own slippage budget and client order ID.
+type SwapSplitLeg struct {
+	Filter        []string `json:"filter"` // single venue, e.g. []string{"binance"}
+	Amount        Amount   `json:"amount"`
+	SlippageBps   int      `json:"slippage_bps"`
+	ClientOrderID *string  `json:"clientOrderId,omitempty"`
+}
+
+// SwapSplitRequest executes one logical swap as N parallel legs across
+// venues to minimize price impact, mirroring how bridge/DEX aggregators
+// split large orders across pools.
+type SwapSplitRequest struct {
+	From    string         `json:"from"`
+	To      string         `json:"to"`
+	Account string         `json:"account"`
+	Legs    []SwapSplitLeg `json:"legs"`
+}
+
+// CompositeOrderStatus aggregates the fills of every leg of a SwapSplit
+// order. Status summarizes the legs: PENDING while any leg is unfilled,
+// FILLED once every leg has filled, FAILED if any leg failed.
+type CompositeOrderStatus struct {
+	CompositeOrderID string                `json:"compositeOrderId"`
+	Status           string                `json:"status"`
+	FilledOut        Amount                `json:"filledOut"`
+	Legs             []OrderStatusResponse `json:"legs"`
+	UpdatedAt        int64                 `json:"updatedAt"`
+}
+
+// SwapSplit submits a split swap and returns the initial composite order
+// status; poll GetCompositeOrderStatus (or watch the orders stream) for leg
+// fills as they complete.
+func (c *BrokerClient) SwapSplit(ctx context.Context, req *SwapSplitRequest, idempotencyKey string) (*CompositeOrderStatus, error) {
+	for i, leg := range req.Legs {
+		rounded, err := c.validateAmount(swapSymbol(req.From, req.To), leg.Amount)
+		if err != nil {
+			return nil, err
+		}
+		req.Legs[i].Amount = rounded
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	responseBody, err := c.makeRequestWithIdempotencyKey(ctx, "POST", "/api/v1/swap/split", body, idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiErr := checkAPIError(responseBody); apiErr != nil {
+		return nil, apiErr
+	}
+
+	var response CompositeOrderStatus
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetCompositeOrderStatus fetches the current aggregated status and
+// per-leg fills of a SwapSplit order.
+func (c *BrokerClient) GetCompositeOrderStatus(ctx context.Context, compositeOrderID string) (*CompositeOrderStatus, error) {
+	path := fmt.Sprintf("/api/v1/swap/split/%s/status", compositeOrderID)
+
+	responseBody, err := c.makeRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiErr := checkAPIError(responseBody); apiErr != nil {
+		return nil, apiErr
+	}
+
+	var response CompositeOrderStatus
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// Signature algorithm identifiers sent in the X-API-SIGN-ALG header.
+const (
+	SignAlgHMACSHA256 = "HMAC-SHA256"
+	SignAlgEd25519    = "ED25519"
+	SignAlgRSASHA256  = "RSA-SHA256"
+)
+
+// Signer signs the canonical request string and identifies which key was
+// used. keyID is sent as X-API-KEY; for the HMAC signer this is simply the
+// API key, while asymmetric signers can use it to identify a registered
+// public key.
+type Signer interface {
+	Algorithm() string
+	Sign(canonicalString []byte) (headerValue string, keyID string, err error)
+}
+
+// BuildCanonicalString assembles the canonical string signed by every
+// Signer implementation: method, path+query, timestamp, nonce and the
+// SHA256 hash of the body, newline-separated.
+func BuildCanonicalString(method, pathWithQuery string, timestamp int64, nonce, bodySHA256 string) []byte {
+	return []byte(fmt.Sprintf("%s\n%s\n%d\n%s\n%s", method, pathWithQuery, timestamp, nonce, bodySHA256))
+}
+
+// hmacSigner implements the original HMAC-SHA256 scheme: the secret key is
+// hashed with SHA256 and base64url-encoded before being used as the HMAC
+// key, so the literal secret is never used as key material directly.
+type hmacSigner struct {
+	apiKey    string
+	secretKey string
+}
+
+// NewHMACSigner creates a Signer using the HMAC-SHA256 scheme.
+func NewHMACSigner(apiKey, secretKey string) Signer {
+	return &hmacSigner{apiKey: apiKey, secretKey: secretKey}
+}
+
+func (s *hmacSigner) Algorithm() string { return SignAlgHMACSHA256 }
+
+func (s *hmacSigner) Sign(canonicalString []byte) (string, string, error) {
+	hash := sha256.Sum256([]byte(s.secretKey))
+	hmacKey := []byte(base64.URLEncoding.EncodeToString(hash[:]))
+
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write(canonicalString)
+	return hex.EncodeToString(mac.Sum(nil)), s.apiKey, nil
+}
+
+// ed25519Signer implements Ed25519 signing, mirroring exchanges that have
+// moved to Ed25519 API keys.
+type ed25519Signer struct {
+	apiKey     string
+	privateKey ed25519.PrivateKey
+}
+
+// NewEd25519Signer creates a Signer using Ed25519.
+func NewEd25519Signer(apiKey string, privateKey ed25519.PrivateKey) Signer {
+	return &ed25519Signer{apiKey: apiKey, privateKey: privateKey}
+}
+
+func (s *ed25519Signer) Algorithm() string { return SignAlgEd25519 }
+
+func (s *ed25519Signer) Sign(canonicalString []byte) (string, string, error) {
+	sig := ed25519.Sign(s.privateKey, canonicalString)
+	return base64.StdEncoding.EncodeToString(sig), s.apiKey, nil
+}
+
+// rsaSigner implements RSA-SHA256 (PKCS#1 v1.5) signing.
+type rsaSigner struct {
+	apiKey     string
+	privateKey *rsa.PrivateKey
+}
+
+// NewRSASigner creates a Signer using RSA-SHA256.
+func NewRSASigner(apiKey string, privateKey *rsa.PrivateKey) Signer {
+	return &rsaSigner{apiKey: apiKey, privateKey: privateKey}
+}
+
+func (s *rsaSigner) Algorithm() string { return SignAlgRSASHA256 }
+
+func (s *rsaSigner) Sign(canonicalString []byte) (string, string, error) {
+	digest := sha256.Sum256(canonicalString)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign with RSA key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(sig), s.apiKey, nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+)
+
+// SignatureTestVector is a known-good (inputs, signature) pair for a Signer
+// implementation, so integrators can verify their own port of the canonical
+// string / signing scheme byte-for-byte against this client.
+type SignatureTestVector struct {
+	Name              string
+	APIKey            string
+	SecretKey         string
+	Method            string
+	PathWithQuery     string
+	Timestamp         int64
+	Nonce             string
+	BodySHA256        string
+	ExpectedSignature string
+}
+
+// hmacTestVectors are reference vectors for the HMAC-SHA256 scheme,
+// including the double-encoded key derivation (secret -> SHA256 ->
+// base64url -> HMAC key) that is otherwise undocumented.
+var hmacTestVectors = []SignatureTestVector{
+	{
+		Name:              "GET balances, empty body",
+		APIKey:            "test-api-key",
+		SecretKey:         "test-secret-key",
+		Method:            "GET",
+		PathWithQuery:     "/api/v1/balances",
+		Timestamp:         1700000000000,
+		Nonce:             "1700000000000000000_123",
+		BodySHA256:        hashBody(nil),
+		ExpectedSignature: "3e098b96ecfadc981b5f4f04cab39a17e8666b59cd8469504b207fb95eb248f3",
+	},
+}
+
+// TestSignatureVectors signs every vector's canonical string with a freshly
+// constructed HMAC signer and checks the result against the recorded
+// signature, so the table above also serves as a byte-for-byte conformance
+// suite for other implementations of the scheme.
+func TestSignatureVectors(t *testing.T) {
+	for _, v := range hmacTestVectors {
+		t.Run(v.Name, func(t *testing.T) {
+			signer := NewHMACSigner(v.APIKey, v.SecretKey)
+			canonical := BuildCanonicalString(v.Method, v.PathWithQuery, v.Timestamp, v.Nonce, v.BodySHA256)
+
+			got, _, err := signer.Sign(canonical)
+			if err != nil {
+				t.Fatalf("sign failed: %v", err)
+			}
+			if got != v.ExpectedSignature {
+				t.Errorf("got signature %s, want %s", got, v.ExpectedSignature)
+			}
+		})
+	}
+}
+
+// Ed25519TestVector is a known-good (seed, signature) pair for the Ed25519
+// scheme. Unlike RSA, an Ed25519 private key is fully determined by a 32-byte
+// seed, so the expected signature can be pinned exactly, the same as the
+// HMAC vectors above.
+type Ed25519TestVector struct {
+	Name              string
+	APIKey            string
+	SeedHex           string
+	Method            string
+	PathWithQuery     string
+	Timestamp         int64
+	Nonce             string
+	BodySHA256        string
+	ExpectedSignature string
+}
+
+// ed25519TestVectors pins the seed -> private key expansion and signing
+// together, so a port that gets either step wrong fails this vector.
+var ed25519TestVectors = []Ed25519TestVector{
+	{
+		Name:              "GET balances, empty body",
+		APIKey:            "test-api-key",
+		SeedHex:           "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20",
+		Method:            "GET",
+		PathWithQuery:     "/api/v1/balances",
+		Timestamp:         1700000000000,
+		Nonce:             "1700000000000000000_123",
+		BodySHA256:        hashBody(nil),
+		ExpectedSignature: "0LL9fdnUZBWMXtzdLtgqSoekRN5uDRyBNBkYeG6nX0tBKeqU68SQuq4VSEe9pZfUQBz75wY66fECu+KqIfeVBw==",
+	},
+}
+
+// TestEd25519SignatureVectors is the Ed25519 counterpart to
+// TestSignatureVectors: it signs every vector's canonical string with a key
+// expanded from the recorded seed and checks the result byte-for-byte.
+func TestEd25519SignatureVectors(t *testing.T) {
+	for _, v := range ed25519TestVectors {
+		t.Run(v.Name, func(t *testing.T) {
+			seed, err := hex.DecodeString(v.SeedHex)
+			if err != nil {
+				t.Fatalf("invalid seed: %v", err)
+			}
+			signer := NewEd25519Signer(v.APIKey, ed25519.NewKeyFromSeed(seed))
+			canonical := BuildCanonicalString(v.Method, v.PathWithQuery, v.Timestamp, v.Nonce, v.BodySHA256)
+
+			got, _, err := signer.Sign(canonical)
+			if err != nil {
+				t.Fatalf("sign failed: %v", err)
+			}
+			if got != v.ExpectedSignature {
+				t.Errorf("got signature %s, want %s", got, v.ExpectedSignature)
+			}
+		})
+	}
+}
+
+// TestRSASignatureRoundTrip covers the RSA-SHA256 signer. A fixed test
+// vector isn't practical here the way it is for HMAC/Ed25519: a reproducible
+// RSA private key means hard-coding its modulus and primes, which is both
+// unreadable and pointless as a conformance aid for other implementations
+// (nobody ports the PKCS#1 v1.5 math by hand). Instead this checks what
+// actually matters: the signer produces a signature that verifies under the
+// corresponding public key for the exact canonical string being signed.
+func TestRSASignatureRoundTrip(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+
+	signer := NewRSASigner("test-api-key", privateKey)
+	canonical := BuildCanonicalString("GET", "/api/v1/balances", 1700000000000, "1700000000000000000_123", hashBody(nil))
+
+	sigB64, keyID, err := signer.Sign(canonical)
+	if err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+	if keyID != "test-api-key" {
+		t.Errorf("got keyID %s, want test-api-key", keyID)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(sigB64)
+	if err != nil {
+		t.Fatalf("signature is not valid base64: %v", err)
+	}
+
+	digest := sha256.Sum256(canonical)
+	if err := rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		t.Errorf("signature did not verify: %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// StreamEventType identifies the kind of payload carried by a StreamEvent.
+type StreamEventType string
+
+const (
+	StreamEventTicker  StreamEventType = "ticker"
+	StreamEventOrder   StreamEventType = "order"
+	StreamEventBalance StreamEventType = "balance"
+)
+
+// TickerUpdate represents a pushed price/estimate update for a trading pair.
+type TickerUpdate struct {
+	Symbol    string `json:"symbol"`
+	Price     string `json:"price"`
+	Timestamp int64  `json:"ts"`
+}
+
+// OrderStreamUpdate represents an order state transition pushed over the stream,
+// e.g. PENDING -> PARTIALLY_FILLED -> FILLED/FAILED.
+type OrderStreamUpdate struct {
+	OrderID       string  `json:"orderId"`
+	Status        string  `json:"status"`
+	FilledOut     string  `json:"filledOut,omitempty"`
+	TxHash        string  `json:"txHash,omitempty"`
+	UpdatedAt     int64   `json:"updatedAt"`
+	ClientOrderID *string `json:"clientOrderId,omitempty"`
+}
+
+// BalanceStreamUpdate represents a pushed balance change.
+type BalanceStreamUpdate struct {
+	Balances []Balance `json:"balances"`
+}
+
+// StreamEvent is a single decoded message delivered by BrokerClient.Stream.
+// Exactly one of Ticker, Order or Balance is populated, matching Type.
+type StreamEvent struct {
+	Type    StreamEventType
+	Ticker  *TickerUpdate
+	Order   *OrderStreamUpdate
+	Balance *BalanceStreamUpdate
+}
+
+// wsURL derives the WebSocket endpoint from the client's base URL.
+func (c *BrokerClient) wsURL() string {
+	url := c.baseURL
+	url = strings.Replace(url, "https://", "wss://", 1)
+	url = strings.Replace(url, "http://", "ws://", 1)
+	return strings.TrimRight(url, "/") + "/ws/v1/stream"
+}
+
+// Stream opens a WebSocket connection and subscribes to the ticker, order and
+// balance channels, delivering decoded events on the returned channel. It
+// authenticates using the same HMAC-SHA256 canonical string scheme as the
+// REST API. The underlying WSClient owns heartbeat handling and reconnection;
+// the returned channel is closed once ctx is cancelled or the connection is
+// terminally lost.
+func (c *BrokerClient) Stream(ctx context.Context) (<-chan *StreamEvent, error) {
+	ws := NewWSClient(c.apiKey, c.secretKey, c.wsURL(), &WSOptions{Compression: true})
+	if err := ws.Connect(); err != nil {
+		return nil, fmt.Errorf("failed to connect stream: %w", err)
+	}
+
+	events := make(chan *StreamEvent, 64)
+
+	emit := func(eventType StreamEventType, build func(data interface{}) (*StreamEvent, error)) MessageHandler {
+		return func(msg *WSMessage) {
+			evt, err := build(msg.Data)
+			if err != nil {
+				return
+			}
+			select {
+			case events <- evt:
+			case <-ctx.Done():
+			}
+		}
+	}
+
+	decode := func(data interface{}, v interface{}) error {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(raw, v)
+	}
+
+	if err := ws.Subscribe("ticker", emit(StreamEventTicker, func(data interface{}) (*StreamEvent, error) {
+		var t TickerUpdate
+		if err := decode(data, &t); err != nil {
+			return nil, err
+		}
+		return &StreamEvent{Type: StreamEventTicker, Ticker: &t}, nil
+	})); err != nil {
+		ws.Close()
+		return nil, fmt.Errorf("failed to subscribe to ticker channel: %w", err)
+	}
+
+	if err := ws.Subscribe("orders", emit(StreamEventOrder, func(data interface{}) (*StreamEvent, error) {
+		var o OrderStreamUpdate
+		if err := decode(data, &o); err != nil {
+			return nil, err
+		}
+		return &StreamEvent{Type: StreamEventOrder, Order: &o}, nil
+	})); err != nil {
+		ws.Close()
+		return nil, fmt.Errorf("failed to subscribe to orders channel: %w", err)
+	}
+
+	if err := ws.Subscribe("balances", emit(StreamEventBalance, func(data interface{}) (*StreamEvent, error) {
+		var b BalanceStreamUpdate
+		if err := decode(data, &b); err != nil {
+			return nil, err
+		}
+		return &StreamEvent{Type: StreamEventBalance, Balance: &b}, nil
+	})); err != nil {
+		ws.Close()
+		return nil, fmt.Errorf("failed to subscribe to balances channel: %w", err)
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-ws.Errors():
+			// Reconnection gave up after MaxReconnectAttempts: the
+			// connection is terminally lost even though our ctx is still
+			// live, so treat it the same as caller cancellation.
+		}
+		// Close alone doesn't stop a readMessages call that already read a
+		// frame off the socket before Close ran; it can still dispatch a
+		// handler afterwards. WaitRead blocks until readMessages has
+		// actually returned, which guarantees no such dispatch is pending,
+		// before WaitHandlers waits out every handler goroutine already
+		// spawned. Only then is it safe to close events, otherwise an
+		// in-flight emit can still pick the send case after the channel is
+		// closed and panic.
+		ws.Close()
+		ws.WaitRead()
+		ws.WaitHandlers()
+		close(events)
+	}()
+
+	return events, nil
+}
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
+	"crypto/tls"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"math/rand"
 	"net/url"
@@ -27,6 +32,7 @@ type WSMessage struct {
 	Signature string      `json:"sig,omitempty"`
 	Data      interface{} `json:"data,omitempty"`
 	Error     string      `json:"error,omitempty"`
+	ReqID     string      `json:"req_id,omitempty"`
 }
 
 // WSClient represents WebSocket client for Broker Trading API
@@ -38,57 +44,264 @@ type WSClient struct {
 	mu        sync.RWMutex
 	handlers  map[string][]MessageHandler
 	connected bool
+	closed    bool
 	ctx       context.Context
 	cancel    context.CancelFunc
+
+	// ReconnectInterval is the initial delay before a reconnect attempt.
+	ReconnectInterval time.Duration
+	// MaxReconnectInterval caps the exponential backoff delay.
+	MaxReconnectInterval time.Duration
+	// MaxReconnectAttempts is the number of reconnect attempts allowed
+	// after a single disconnect before giving up and surfacing a terminal
+	// error on Errors(). Zero means retry forever.
+	MaxReconnectAttempts int
+	// OnReconnect, if set, is called after a reconnect successfully
+	// re-authenticates and resubscribes to every previous channel.
+	OnReconnect func()
+	// OnDisconnect, if set, is called as soon as the connection drops,
+	// before any reconnect attempt is made.
+	OnDisconnect func()
+
+	// PingInterval is how often a ping control frame is sent to keep the
+	// connection alive and detect a half-open TCP connection.
+	PingInterval time.Duration
+	// ReadTimeout is the read deadline applied before every read; it is
+	// extended on every received pong, so a stalled peer surfaces a read
+	// error (and triggers reconnect) within ReadTimeout of going silent.
+	ReadTimeout time.Duration
+
+	errCh   chan error
+	writeCh chan *wsWriteRequest
+
+	pendingMu sync.Mutex
+	pending   map[string]chan *WSMessage
+
+	handlerWG sync.WaitGroup
+	// readDone is closed when readMessages returns, i.e. once it is
+	// guaranteed no further MessageHandler will be dispatched via
+	// handleMessage. Callers that need "no handler is running and none
+	// will start" (Stream's teardown) must wait on this before waiting on
+	// handlerWG, otherwise a frame already read off the socket can still be
+	// dispatched after WaitHandlers returns.
+	readDone chan struct{}
+
+	opts WSOptions
+}
+
+// WSOptions configures optional dialer behavior for WSClient. The zero value
+// is a plain, uncompressed connection with the default TLS configuration and
+// no subprotocol negotiation.
+type WSOptions struct {
+	// Compression enables permessage-deflate negotiation with the server.
+	// The server may still decline it; EnableWriteCompression only takes
+	// effect once the handshake confirms the extension was accepted.
+	Compression bool
+	// Subprotocols lists the WebSocket subprotocols offered during the
+	// handshake, in preference order.
+	Subprotocols []string
+	// TLSClientConfig overrides the dialer's TLS configuration, e.g. to
+	// pin a custom CA bundle. Nil uses gorilla/websocket's default.
+	TLSClientConfig *tls.Config
+}
+
+// wsWriteRequest is a single outbound frame, either a JSON message or a
+// control frame (ping/pong), serialized through writeLoop since
+// gorilla/websocket forbids concurrent writes on one connection.
+type wsWriteRequest struct {
+	msg     *WSMessage
+	control *wsControlFrame
+	result  chan error
+}
+
+type wsControlFrame struct {
+	messageType int
+	data        []byte
+	deadline    time.Time
 }
 
 // MessageHandler is a function to handle incoming messages
 type MessageHandler func(msg *WSMessage)
 
-// NewWSClient creates a new WebSocket client
-func NewWSClient(apiKey, secretKey, wsURL string) *WSClient {
+// NewWSClient creates a new WebSocket client. opts may be nil, which is
+// equivalent to a zero-value WSOptions (no compression, no subprotocols,
+// default TLS configuration).
+func NewWSClient(apiKey, secretKey, wsURL string, opts *WSOptions) *WSClient {
+	if opts == nil {
+		opts = &WSOptions{}
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
-	return &WSClient{
-		apiKey:    apiKey,
-		secretKey: secretKey,
-		wsURL:     wsURL,
-		handlers:  make(map[string][]MessageHandler),
-		ctx:       ctx,
-		cancel:    cancel,
+	ws := &WSClient{
+		apiKey:               apiKey,
+		secretKey:            secretKey,
+		wsURL:                wsURL,
+		handlers:             make(map[string][]MessageHandler),
+		ctx:                  ctx,
+		cancel:               cancel,
+		ReconnectInterval:    time.Second,
+		MaxReconnectInterval: 30 * time.Second,
+		MaxReconnectAttempts: 10,
+		PingInterval:         30 * time.Second,
+		ReadTimeout:          60 * time.Second,
+		errCh:                make(chan error, 1),
+		writeCh:              make(chan *wsWriteRequest, 32),
+		pending:              make(map[string]chan *WSMessage),
+		readDone:             make(chan struct{}),
+		opts:                 *opts,
+	}
+
+	go ws.writeLoop()
+
+	return ws
+}
+
+// writeLoop is the single goroutine that ever calls a Write* method on the
+// current connection, serializing ping control frames and JSON messages as
+// required by gorilla/websocket. It runs for the lifetime of the client and
+// transparently picks up the new connection after a reconnect.
+func (ws *WSClient) writeLoop() {
+	for {
+		select {
+		case <-ws.ctx.Done():
+			return
+		case req := <-ws.writeCh:
+			ws.mu.RLock()
+			conn := ws.conn
+			ws.mu.RUnlock()
+
+			var err error
+			switch {
+			case conn == nil:
+				err = fmt.Errorf("WebSocket not connected")
+			case req.control != nil:
+				err = conn.WriteControl(req.control.messageType, req.control.data, req.control.deadline)
+			default:
+				err = conn.WriteJSON(req.msg)
+			}
+
+			if req.result != nil {
+				req.result <- err
+			}
+		}
 	}
 }
 
+// Errors returns a channel that receives a single terminal error once
+// reconnection has been given up on (MaxReconnectAttempts exceeded).
+func (ws *WSClient) Errors() <-chan error {
+	return ws.errCh
+}
+
 // Connect establishes WebSocket connection and authenticates
 func (ws *WSClient) Connect() error {
+	if err := ws.dial(); err != nil {
+		return err
+	}
+
+	// Start message reading and keepalive pings in background
+	go ws.readMessages()
+	go ws.pingLoop()
+
+	// Authenticate
+	if err := ws.authenticate(); err != nil {
+		ws.Close()
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+
+	log.Println("Authentication successful")
+	return nil
+}
+
+// dialer builds the websocket.Dialer used for every connection attempt. It
+// returns the package default unless WSOptions asks for something else, so
+// the common case pays no extra allocation.
+func (ws *WSClient) dialer() *websocket.Dialer {
+	if !ws.opts.Compression && len(ws.opts.Subprotocols) == 0 && ws.opts.TLSClientConfig == nil {
+		return websocket.DefaultDialer
+	}
+
+	dialer := *websocket.DefaultDialer
+	dialer.EnableCompression = ws.opts.Compression
+	dialer.Subprotocols = ws.opts.Subprotocols
+	dialer.TLSClientConfig = ws.opts.TLSClientConfig
+	return &dialer
+}
+
+// dial parses the WebSocket URL, connects and marks the client connected,
+// without starting the read loop or authenticating. Shared by Connect and
+// the reconnect loop.
+func (ws *WSClient) dial() error {
 	u, err := url.Parse(ws.wsURL)
 	if err != nil {
 		return fmt.Errorf("invalid WebSocket URL: %w", err)
 	}
 
 	log.Printf("Connecting to WebSocket: %s", ws.wsURL)
-	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	conn, _, err := ws.dialer().Dial(u.String(), nil)
 	if err != nil {
 		return fmt.Errorf("failed to connect to WebSocket: %w", err)
 	}
 
+	if ws.opts.Compression {
+		conn.EnableWriteCompression(true)
+		conn.SetCompressionLevel(flate.BestSpeed)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(ws.ReadTimeout))
+	conn.SetPongHandler(func(appData string) error {
+		return conn.SetReadDeadline(time.Now().Add(ws.ReadTimeout))
+	})
+	conn.SetPingHandler(func(appData string) error {
+		req := &wsWriteRequest{control: &wsControlFrame{
+			messageType: websocket.PongMessage,
+			data:        []byte(appData),
+			deadline:    time.Now().Add(5 * time.Second),
+		}}
+		select {
+		case ws.writeCh <- req:
+		default:
+			log.Println("WebSocket write queue full, dropping pong")
+		}
+		return nil
+	})
+
 	ws.mu.Lock()
+	stale := ws.conn
 	ws.conn = conn
 	ws.connected = true
 	ws.mu.Unlock()
 
+	if stale != nil {
+		stale.Close()
+	}
+
 	log.Println("WebSocket connected successfully")
+	return nil
+}
 
-	// Start message reading in background
-	go ws.readMessages()
+// pingLoop periodically sends a ping control frame so a half-open TCP
+// connection surfaces a write/read error instead of hanging forever.
+func (ws *WSClient) pingLoop() {
+	ticker := time.NewTicker(ws.PingInterval)
+	defer ticker.Stop()
 
-	// Authenticate
-	if err := ws.authenticate(); err != nil {
-		ws.Close()
-		return fmt.Errorf("authentication failed: %w", err)
+	for {
+		select {
+		case <-ws.ctx.Done():
+			return
+		case <-ticker.C:
+			req := &wsWriteRequest{control: &wsControlFrame{
+				messageType: websocket.PingMessage,
+				deadline:    time.Now().Add(5 * time.Second),
+			}}
+			select {
+			case ws.writeCh <- req:
+			case <-ws.ctx.Done():
+				return
+			}
+		}
 	}
-
-	log.Println("Authentication successful")
-	return nil
 }
 
 // authenticate sends authentication message
@@ -158,20 +371,34 @@ func (ws *WSClient) Unsubscribe(channel string) error {
 	return ws.sendMessage(&unsubscribeMsg)
 }
 
-// sendMessage sends a message to WebSocket
+// sendMessage sends a message to WebSocket, serialized through writeLoop
+// alongside ping/pong control frames.
 func (ws *WSClient) sendMessage(msg *WSMessage) error {
-	ws.mu.RLock()
-	defer ws.mu.RUnlock()
+	result := make(chan error, 1)
+	req := &wsWriteRequest{msg: msg, result: result}
 
-	if !ws.connected || ws.conn == nil {
-		return fmt.Errorf("WebSocket not connected")
+	select {
+	case ws.writeCh <- req:
+	case <-ws.ctx.Done():
+		return fmt.Errorf("WebSocket client closed")
 	}
 
-	return ws.conn.WriteJSON(msg)
+	select {
+	case err := <-result:
+		return err
+	case <-ws.ctx.Done():
+		return fmt.Errorf("WebSocket client closed")
+	}
 }
 
-// readMessages reads messages from WebSocket in a loop
+// readMessages reads messages from WebSocket in a loop. A read error
+// triggers a reconnect attempt rather than tearing down the client; only a
+// context cancellation or exhausting MaxReconnectAttempts ends the loop.
+// readDone is closed once this function returns, i.e. once it is guaranteed
+// no further call to handleMessage (and so no further handler dispatch) can
+// happen; see WaitRead.
 func (ws *WSClient) readMessages() {
+	defer close(ws.readDone)
 	defer ws.Close()
 
 	for {
@@ -181,7 +408,6 @@ func (ws *WSClient) readMessages() {
 		default:
 		}
 
-		var msg WSMessage
 		ws.mu.RLock()
 		conn := ws.conn
 		ws.mu.RUnlock()
@@ -190,10 +416,28 @@ func (ws *WSClient) readMessages() {
 			return
 		}
 
-		err := conn.ReadJSON(&msg)
+		conn.SetReadDeadline(time.Now().Add(ws.ReadTimeout))
+		frameType, payload, err := conn.ReadMessage()
 		if err != nil {
 			log.Printf("WebSocket read error: %v", err)
-			return
+			if !ws.reconnect() {
+				return
+			}
+			continue
+		}
+
+		if frameType == websocket.BinaryMessage {
+			payload, err = ungzip(payload)
+			if err != nil {
+				log.Printf("WebSocket gzip decode error: %v", err)
+				continue
+			}
+		}
+
+		var msg WSMessage
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			log.Printf("WebSocket decode error: %v", err)
+			continue
 		}
 
 		ws.handleMessage(&msg)
@@ -202,6 +446,11 @@ func (ws *WSClient) readMessages() {
 
 // handleMessage handles incoming WebSocket messages
 func (ws *WSClient) handleMessage(msg *WSMessage) {
+	// Route responses to a pending synchronous call, if any.
+	if msg.ReqID != "" && ws.routeToPending(msg) {
+		return
+	}
+
 	// Handle error messages
 	if msg.Error != "" {
 		log.Printf("WebSocket error: %s", msg.Error)
@@ -224,21 +473,151 @@ func (ws *WSClient) handleMessage(msg *WSMessage) {
 		ws.mu.RUnlock()
 
 		for _, handler := range handlers {
-			go handler(msg)
+			ws.handlerWG.Add(1)
+			go func(h MessageHandler) {
+				defer ws.handlerWG.Done()
+				h(msg)
+			}(handler)
 		}
 	}
 }
 
+// WaitRead blocks until readMessages has returned, which guarantees no
+// handleMessage call is in flight or still to come — including one that
+// already read a frame off the socket before Close ran. Callers that need
+// "no handler is running and none will start" must call WaitRead before
+// WaitHandlers; calling Close (or WaitHandlers) alone is not enough, since
+// readMessages can still be mid-dispatch when Close returns.
+func (ws *WSClient) WaitRead() {
+	<-ws.readDone
+}
+
+// WaitHandlers blocks until every in-flight MessageHandler invocation
+// dispatched by handleMessage has returned. Callers must first ensure no
+// further messages will be dispatched — call WaitRead (not just Close)
+// beforehand — otherwise a new handler can start after WaitHandlers
+// returns.
+func (ws *WSClient) WaitHandlers() {
+	ws.handlerWG.Wait()
+}
+
+// ungzip decompresses a gzip-compressed binary frame, as sent by the server
+// for high-throughput channels (e.g. ticker/order book updates).
+func ungzip(payload []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gzip reader: %w", err)
+	}
+	defer r.Close()
+
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress frame: %w", err)
+	}
+	return decompressed, nil
+}
+
 // generateNonce creates unique nonce
 func (ws *WSClient) generateNonce() string {
 	return fmt.Sprintf("%d_%d", time.Now().UnixNano(), rand.Int63())
 }
 
+// reconnect is invoked from the read loop after a connection drop. It
+// closes the stale connection, calls OnDisconnect, then retries dialing
+// with jittered exponential backoff up to MaxReconnectAttempts. On success
+// it re-authenticates, resubscribes every previously registered channel and
+// calls OnReconnect. It returns false if the client was explicitly closed
+// or reconnection was abandoned, in which case the caller should stop.
+func (ws *WSClient) reconnect() bool {
+	ws.mu.Lock()
+	if ws.closed {
+		ws.mu.Unlock()
+		return false
+	}
+	if ws.conn != nil {
+		ws.conn.Close()
+		ws.conn = nil
+	}
+	ws.connected = false
+	onDisconnect := ws.OnDisconnect
+	ws.mu.Unlock()
+
+	if onDisconnect != nil {
+		onDisconnect()
+	}
+
+	delay := ws.ReconnectInterval
+	for attempt := 1; ws.MaxReconnectAttempts == 0 || attempt <= ws.MaxReconnectAttempts; attempt++ {
+		select {
+		case <-ws.ctx.Done():
+			return false
+		case <-time.After(jitter(delay)):
+		}
+
+		log.Printf("WebSocket reconnect attempt %d", attempt)
+
+		if err := ws.dial(); err == nil {
+			if err := ws.authenticate(); err != nil {
+				log.Printf("WebSocket reauthentication failed: %v", err)
+			} else {
+				ws.resubscribeAll()
+				if ws.OnReconnect != nil {
+					ws.OnReconnect()
+				}
+				return true
+			}
+		} else {
+			log.Printf("WebSocket reconnect failed: %v", err)
+		}
+
+		delay *= 2
+		if delay > ws.MaxReconnectInterval {
+			delay = ws.MaxReconnectInterval
+		}
+	}
+
+	select {
+	case ws.errCh <- fmt.Errorf("giving up after %d reconnect attempts", ws.MaxReconnectAttempts):
+	default:
+	}
+	return false
+}
+
+// resubscribeAll re-issues a subscribe message for every channel with
+// registered handlers, so callbacks keep receiving data transparently after
+// a reconnect without callers needing to call Subscribe again.
+func (ws *WSClient) resubscribeAll() {
+	ws.mu.RLock()
+	channels := make([]string, 0, len(ws.handlers))
+	for channel := range ws.handlers {
+		channels = append(channels, channel)
+	}
+	ws.mu.RUnlock()
+
+	for _, channel := range channels {
+		msg := WSMessage{Op: "subscribe", Channel: channel}
+		if err := ws.sendMessage(&msg); err != nil {
+			log.Printf("Failed to resubscribe to %s: %v", channel, err)
+		}
+	}
+}
+
+// jitter returns d plus up to 20% random jitter, to avoid many clients
+// reconnecting in lockstep.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/5+1))
+}
+
 // Close closes WebSocket connection
 func (ws *WSClient) Close() {
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
 
+	ws.closed = true
+
 	if ws.cancel != nil {
 		ws.cancel()
 	}
@@ -354,5 +733,6 @@ func (ws *WSClient) createSignedMessage(operation string, data interface{}) *WSM
 		Nonce:     nonce,
 		Signature: signature,
 		Data:      data,
+		ReqID:     ws.generateReqID(),
 	}
 }
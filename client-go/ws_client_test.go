@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// simulatedBalanceFrame builds a BalanceStreamUpdate payload representative
+// of a real balances push: one entry per instrument, as sent over the
+// "balances" channel.
+func simulatedBalanceFrame(n int) []byte {
+	update := BalanceStreamUpdate{Balances: make([]Balance, n)}
+	for i := range update.Balances {
+		update.Balances[i] = Balance{
+			Asset:  fmt.Sprintf("ASSET-%04d", i),
+			Total:  MustAmount("12345.6789"),
+			Locked: MustAmount("0"),
+		}
+	}
+	raw, err := json.Marshal(update)
+	if err != nil {
+		panic(err)
+	}
+	return raw
+}
+
+// BenchmarkBalanceStreamCompression measures the payload-size reduction and
+// round-trip decode cost for a simulated balances stream frame using raw
+// DEFLATE at flate.BestSpeed, matching what dial's permessage-deflate
+// negotiation (EnableWriteCompression + SetCompressionLevel(flate.BestSpeed))
+// actually produces on the wire — not gzip, which adds its own framing.
+func BenchmarkBalanceStreamCompression(b *testing.B) {
+	payload := simulatedBalanceFrame(200)
+
+	var compressed bytes.Buffer
+	fw, err := flate.NewWriter(&compressed, flate.BestSpeed)
+	if err != nil {
+		b.Fatalf("flate writer failed: %v", err)
+	}
+	if _, err := fw.Write(payload); err != nil {
+		b.Fatalf("flate write failed: %v", err)
+	}
+	if err := fw.Close(); err != nil {
+		b.Fatalf("flate close failed: %v", err)
+	}
+
+	b.ReportMetric(float64(len(payload)), "uncompressed-bytes")
+	b.ReportMetric(float64(compressed.Len()), "compressed-bytes")
+	b.ReportMetric(float64(len(payload))/float64(compressed.Len()), "ratio")
+
+	compressedBytes := compressed.Bytes()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fr := flate.NewReader(bytes.NewReader(compressedBytes))
+		if _, err := io.Copy(io.Discard, fr); err != nil {
+			b.Fatalf("flate decode failed: %v", err)
+		}
+		fr.Close()
+	}
+}
@@ -18,7 +18,7 @@ func runWebSocketExample() {
 	wsURL := "ws://localhost:8080/ws/v1/stream"
 
 	// Create WebSocket client
-	wsClient := NewWSClient(apiKey, secretKey, wsURL)
+	wsClient := NewWSClient(apiKey, secretKey, wsURL, nil)
 
 	// Connect to WebSocket
 	fmt.Println("=== Connecting to WebSocket ===")
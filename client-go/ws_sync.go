@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// defaultSyncTimeout bounds how long a Sync call waits for a response when
+// the caller's context has no deadline of its own.
+const defaultSyncTimeout = 10 * time.Second
+
+// generateReqID creates a unique request ID used to correlate a WSMessage
+// with its server response.
+func (ws *WSClient) generateReqID() string {
+	return fmt.Sprintf("req_%d_%d", time.Now().UnixNano(), rand.Int63())
+}
+
+// registerPending allocates the response channel for reqID and stores it so
+// handleMessage can deliver the matching response.
+func (ws *WSClient) registerPending(reqID string) chan *WSMessage {
+	ch := make(chan *WSMessage, 1)
+	ws.pendingMu.Lock()
+	ws.pending[reqID] = ch
+	ws.pendingMu.Unlock()
+	return ch
+}
+
+// unregisterPending removes reqID's response channel, e.g. after a timeout.
+func (ws *WSClient) unregisterPending(reqID string) {
+	ws.pendingMu.Lock()
+	delete(ws.pending, reqID)
+	ws.pendingMu.Unlock()
+}
+
+// routeToPending delivers msg to the channel waiting on msg.ReqID, if any,
+// and reports whether a waiter was found.
+func (ws *WSClient) routeToPending(msg *WSMessage) bool {
+	ws.pendingMu.Lock()
+	ch, ok := ws.pending[msg.ReqID]
+	if ok {
+		delete(ws.pending, msg.ReqID)
+	}
+	ws.pendingMu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	select {
+	case ch <- msg:
+	default:
+	}
+	return true
+}
+
+// sendAndWait sends msg (which must already carry a ReqID, e.g. from
+// createSignedMessage) and blocks until a response with the same ReqID
+// arrives, ctx is done, or, when ctx has no deadline, defaultSyncTimeout
+// elapses.
+func (ws *WSClient) sendAndWait(ctx context.Context, msg *WSMessage) (*WSMessage, error) {
+	ch := ws.registerPending(msg.ReqID)
+	defer ws.unregisterPending(msg.ReqID)
+
+	if err := ws.sendMessage(msg); err != nil {
+		return nil, err
+	}
+
+	// Only fall back to defaultSyncTimeout when the caller didn't already
+	// give ctx a deadline of its own; otherwise ctx.Done() below is what
+	// bounds the wait, and a shorter hard-coded timer would silently cap it.
+	var timeoutCh <-chan time.Time
+	if _, ok := ctx.Deadline(); !ok {
+		timer := time.NewTimer(defaultSyncTimeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Error != "" {
+			return nil, fmt.Errorf("operation %s failed: %s", msg.Op, resp.Error)
+		}
+		return resp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timeoutCh:
+		return nil, fmt.Errorf("timed out waiting for response to %s (req_id=%s)", msg.Op, msg.ReqID)
+	}
+}
+
+// decodeWSData re-marshals a decoded interface{} payload into v, since
+// WSMessage.Data arrives as generic JSON via encoding/json.
+func decodeWSData(data interface{}, v interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// EstimateSwapSync estimates a swap and waits for the server's response
+// instead of only logging it in handleMessage.
+func (ws *WSClient) EstimateSwapSync(ctx context.Context, amountIn, assetIn, assetOut string) (*EstimateResponse, error) {
+	msg := ws.createSignedMessage("estimate", map[string]interface{}{
+		"amountIn": amountIn,
+		"assetIn":  assetIn,
+		"assetOut": assetOut,
+	})
+
+	resp, err := ws.sendAndWait(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var estimate EstimateResponse
+	if err := decodeWSData(resp.Data, &estimate); err != nil {
+		return nil, fmt.Errorf("failed to decode estimate response: %w", err)
+	}
+	return &estimate, nil
+}
+
+// DoSwapSync executes a swap and waits for the server's response.
+func (ws *WSClient) DoSwapSync(ctx context.Context, amountIn, assetIn, assetOut string) (*SwapResponse, error) {
+	msg := ws.createSignedMessage("swap", map[string]interface{}{
+		"amountIn": amountIn,
+		"assetIn":  assetIn,
+		"assetOut": assetOut,
+	})
+
+	resp, err := ws.sendAndWait(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var swap SwapResponse
+	if err := decodeWSData(resp.Data, &swap); err != nil {
+		return nil, fmt.Errorf("failed to decode swap response: %w", err)
+	}
+	return &swap, nil
+}
+
+// GetOrderStatusSync fetches an order's status and waits for the server's
+// response.
+func (ws *WSClient) GetOrderStatusSync(ctx context.Context, orderID string) (*OrderStatusResponse, error) {
+	msg := ws.createSignedMessage("order_status", map[string]interface{}{
+		"id": orderID,
+	})
+
+	resp, err := ws.sendAndWait(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var status OrderStatusResponse
+	if err := decodeWSData(resp.Data, &status); err != nil {
+		return nil, fmt.Errorf("failed to decode order status response: %w", err)
+	}
+	return &status, nil
+}
+
+// GetBalancesSync fetches account balances and waits for the server's
+// response.
+func (ws *WSClient) GetBalancesSync(ctx context.Context) (*BalanceResponse, error) {
+	msg := ws.createSignedMessage("balances", nil)
+
+	resp, err := ws.sendAndWait(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var balances BalanceResponse
+	if err := decodeWSData(resp.Data, &balances); err != nil {
+		return nil, fmt.Errorf("failed to decode balances response: %w", err)
+	}
+	return &balances, nil
+}